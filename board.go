@@ -2,66 +2,108 @@ package main
 
 import (
 	"fmt"
-	"strings"
 	"strconv"
+	"strings"
+	"sync/atomic"
 )
 
-var blockOffsets = []int{0, 1, 2, 9, 10, 11, 18, 19, 20}
+// opsCnt counts candidate checks across the whole process, for the rough
+// "how much search did this take" diagnostics Solve prints and Difficulty
+// rates by (see searchEffort).
+// It's an atomic.Int64 rather than a plain int because deterministicSolve
+// can run concurrently from more than one Solver branch at once.
+var opsCnt atomic.Int64
+
+// Board is an N x N Sudoku grid made up of boxRows x boxCols boxes, where
+// N = boxRows * boxCols. The classic 9x9 puzzle is boxRows = boxCols = 3;
+// NewBoardN also supports variants such as 4x4 (2x2 boxes), 6x6 (2x3 boxes)
+// or 16x16 hexadoku (4x4 boxes).
+type Board struct {
+	cells            []int
+	boxRows, boxCols int
+}
+
+// NewBoard creates an empty, classic 9x9 board.
+func NewBoard() Board {
+	return NewBoardN(3, 3)
+}
 
-var opsCnt = 0
+// NewBoardN creates an empty board made up of boxRows x boxCols boxes, so
+// N = boxRows * boxCols cells per side.
+func NewBoardN(boxRows, boxCols int) Board {
+	n := boxRows * boxCols
+	return Board{
+		cells:   make([]int, n*n),
+		boxRows: boxRows,
+		boxCols: boxCols,
+	}
+}
 
-type Board []int
+// N returns the side length of the board, i.e. the number of cells per row,
+// column and box.
+func (b Board) N() int {
+	return b.boxRows * b.boxCols
+}
 
-func NewBoard() Board {
-	return make([]int, 81)
+// newBoardFromCells wraps an existing, fully populated cell slice into a
+// Board of the given box dimensions, without allocating a fresh one. Used
+// for hardcoded example/test boards.
+func newBoardFromCells(boxRows, boxCols int, cells []int) Board {
+	return Board{cells: cells, boxRows: boxRows, boxCols: boxCols}
 }
 
 func (b Board) Set(x, y, v int) {
-	idx := x + y * 9
+	n := b.N()
+	idx := x + y*n
 
-	if idx < 0 || idx > 80 {
+	if idx < 0 || idx >= n*n {
 		panic(fmt.Sprintf("Cannot set field with index %d", idx))
 	}
 
-	b[idx] = v
+	b.cells[idx] = v
 }
 
 func (b Board) Get(x, y int) int {
-	idx := x + y * 9
+	n := b.N()
+	idx := x + y*n
 
-	if idx < 0 || idx > 80 {
+	if idx < 0 || idx >= n*n {
 		panic(fmt.Sprintf("Cannot get field with index %d", idx))
 	}
 
-	return b[idx]
+	return b.cells[idx]
 }
 
 func (b Board) valuePossibleAt(v, idx int) bool {
-	opsCnt++
+	opsCnt.Add(1)
+
+	n := b.N()
 
 	// Check row
-	startOfRowIdx := idx / 9 * 9
-	for i := 0; i < 9; i++ {
-		if b[startOfRowIdx + i] == v {
+	startOfRowIdx := idx / n * n
+	for i := 0; i < n; i++ {
+		if b.cells[startOfRowIdx+i] == v {
 			return false
 		}
 	}
 
 	// Check column
-	column := idx % 9
-	for i := 0; i < 9; i++ {
-		if b[column + i * 9] == v {
+	column := idx % n
+	for i := 0; i < n; i++ {
+		if b.cells[column+i*n] == v {
 			return false
 		}
 	}
 
 	// Check block
-	row := idx / 9
-	topLeftIdx := row / 3 * 3 * 9 + column / 3 * 3
+	row := idx / n
+	topLeftIdx := row/b.boxRows*b.boxRows*n + column/b.boxCols*b.boxCols
 
-	for _, offset := range blockOffsets {
-		if b[topLeftIdx + offset] == v {
-			return false
+	for br := 0; br < b.boxRows; br++ {
+		for bc := 0; bc < b.boxCols; bc++ {
+			if b.cells[topLeftIdx+br*n+bc] == v {
+				return false
+			}
 		}
 	}
 
@@ -71,11 +113,12 @@ func (b Board) valuePossibleAt(v, idx int) bool {
 func (b Board) String() string {
 	var sb strings.Builder
 
-	for i := 0; i < 81; i++ {
-		if i % 9 == 0 {
+	n := b.N()
+	for i, v := range b.cells {
+		if i%n == 0 {
 			sb.WriteString("\n")
 		}
-		sb.WriteString(strconv.Itoa(b[i]))
+		sb.WriteString(strconv.Itoa(v))
 		sb.WriteRune(' ')
 	}
 
@@ -84,34 +127,41 @@ func (b Board) String() string {
 	return sb.String()
 }
 
+func (b Board) findNextFieldNotSetAlready(idx int) int {
+	total := len(b.cells)
+	for idx < total && b.cells[idx] != 0 {
+		idx = idx + 1
+	}
+
+	return idx
+}
+
 func (b Board) Solve() {
-	opsCnt = 0
+	opsCnt.Store(0)
 	fmt.Printf("Trying to find solution for: %s\n", b)
 
 	if b.solveInner(0) {
-		fmt.Printf("Found valid solution after %d steps: %s", opsCnt, b)
+		fmt.Printf("Found valid solution after %d steps: %s", opsCnt.Load(), b)
 	} else {
-		fmt.Printf("Could not find a valid solution for this puzzle after %d steps!\n", opsCnt)
+		fmt.Printf("Could not find a valid solution for this puzzle after %d steps!\n", opsCnt.Load())
 	}
 }
 
 func (b Board) solveInner(idx int) bool {
-	// fmt.Printf("%d: %s\n", idx, b)
-
 	// Find next field not being set already
-	for idx < 81 && b[idx] != 0 {
-		idx = idx + 1
-	}
+	idx = b.findNextFieldNotSetAlready(idx)
 
-	if idx == 81 {
+	if idx == len(b.cells) {
 		// All fields are set, we found a solution for this puzzle
 		return true
 	}
 
+	n := b.N()
+
 	// Iterate over all possible values for this field
-	for i := 1; i < 10; i++ {
+	for i := 1; i <= n; i++ {
 		if b.valuePossibleAt(i, idx) {
-			b[idx] = i
+			b.cells[idx] = i
 			if b.solveInner(idx + 1) {
 				return true
 			}
@@ -119,7 +169,7 @@ func (b Board) solveInner(idx int) bool {
 	}
 
 	// Reset field for backtracking
-	b[idx] = 0
+	b.cells[idx] = 0
 
 	return false
-}
\ No newline at end of file
+}
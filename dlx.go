@@ -0,0 +1,228 @@
+package main
+
+// dlxNode is a single 1-entry in Knuth's toroidal doubly-linked exact-cover
+// matrix. Every node knows its four neighbours and the column header it
+// belongs to; rows are not represented explicitly, they just fall out of the
+// left/right links of the nodes that make them up.
+type dlxNode struct {
+	left, right, up, down *dlxNode
+	column                *dlxColumn
+	row                   int // index into DLXSolver.choices, identifying the (r, c, n) choice
+}
+
+// dlxColumn is the header of one of the 4*N*N constraint columns (N*N cell +
+// N*N row + N*N column + N*N box constraints, N being the board's side
+// length). size is kept up to date by cover/uncover so Algorithm X can
+// always pick the smallest column.
+type dlxColumn struct {
+	dlxNode
+	size int
+	name int
+}
+
+// dlxChoice is the (row, col, value) a matrix row stands for.
+type dlxChoice struct {
+	row, col, val int
+}
+
+// DLXSolver solves a Sudoku board by modelling it as an exact-cover problem
+// and running Knuth's Algorithm X ("Dancing Links") over it. On grids with
+// few clues it vastly outperforms the plain backtracking Solve().
+type DLXSolver struct {
+	root    *dlxColumn
+	columns []*dlxColumn
+	choices []dlxChoice // parallel to the matrix rows, indexed by dlxNode.row
+	board   Board
+}
+
+// NewDLXSolver builds the exact-cover matrix for b. Cells that are already
+// filled in b only contribute their single matching row, so the matrix
+// already reflects the puzzle's prefilled clues.
+func NewDLXSolver(b Board) DLXSolver {
+	n := b.N()
+
+	s := DLXSolver{
+		root:    &dlxColumn{name: -1},
+		columns: make([]*dlxColumn, 4*n*n),
+		board:   b,
+	}
+	s.root.left = &s.root.dlxNode
+	s.root.right = &s.root.dlxNode
+	s.root.up = &s.root.dlxNode
+	s.root.down = &s.root.dlxNode
+
+	for i := range s.columns {
+		col := &dlxColumn{name: i}
+		col.up = &col.dlxNode
+		col.down = &col.dlxNode
+		col.column = col
+		s.columns[i] = col
+
+		last := s.root.left
+		col.left = last
+		col.right = &s.root.dlxNode
+		last.right = &col.dlxNode
+		s.root.left = &col.dlxNode
+	}
+
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			existing := b.Get(c, r)
+			for v := 1; v <= n; v++ {
+				if existing != 0 && existing != v {
+					continue
+				}
+				s.addRow(r, c, v)
+			}
+		}
+	}
+
+	return s
+}
+
+// dlxColumnsFor returns the 4 column indices a placement of v at (r, c)
+// covers on an N x N board made up of boxRows x boxCols boxes.
+func dlxColumnsFor(r, c, v, n, boxRows, boxCols int) [4]int {
+	boxesAcross := n / boxCols
+	box := r/boxRows*boxesAcross + c/boxCols
+	return [4]int{
+		r*n + c,
+		n*n + r*n + (v - 1),
+		2*n*n + c*n + (v - 1),
+		3*n*n + box*n + (v - 1),
+	}
+}
+
+// addRow links one matrix row, covering the 4 columns the placement (r, c, v)
+// hits, and records the choice it represents.
+func (s *DLXSolver) addRow(r, c, v int) {
+	n := s.board.N()
+	rowIdx := len(s.choices)
+	s.choices = append(s.choices, dlxChoice{row: r, col: c, val: v})
+
+	var first, prev *dlxNode
+	for _, colIdx := range dlxColumnsFor(r, c, v, n, s.board.boxRows, s.board.boxCols) {
+		col := s.columns[colIdx]
+
+		node := &dlxNode{column: col, row: rowIdx}
+
+		node.up = col.up
+		node.down = &col.dlxNode
+		col.up.down = node
+		col.up = node
+		col.size++
+
+		if first == nil {
+			first = node
+			node.left = node
+			node.right = node
+		} else {
+			node.left = prev
+			node.right = first
+			prev.right = node
+			first.left = node
+		}
+		prev = node
+	}
+}
+
+// cover removes column c from the header list and removes every row that
+// has a 1 in c from all the other columns it intersects.
+func cover(c *dlxColumn) {
+	c.right.left = c.left
+	c.left.right = c.right
+
+	for i := c.down; i != &c.dlxNode; i = i.down {
+		for j := i.right; j != i; j = j.right {
+			j.down.up = j.up
+			j.up.down = j.down
+			j.column.size--
+		}
+	}
+}
+
+// uncover is the exact inverse of cover, restoring column c and every row
+// that was removed alongside it.
+func uncover(c *dlxColumn) {
+	for i := c.up; i != &c.dlxNode; i = i.up {
+		for j := i.left; j != i; j = j.left {
+			j.column.size++
+			j.down.up = j
+			j.up.down = j
+		}
+	}
+
+	c.right.left = &c.dlxNode
+	c.left.right = &c.dlxNode
+}
+
+// Solve runs Algorithm X over the matrix and returns the completed board.
+// It panics if the puzzle has no solution, matching the assumption that
+// callers only ever feed it valid Sudoku grids.
+func (s DLXSolver) Solve() Board {
+	result := NewBoardN(s.board.boxRows, s.board.boxCols)
+	copy(result.cells, s.board.cells)
+
+	solution := make([]int, 0, len(s.board.cells))
+	if !s.search(&solution) {
+		panic("DLXSolver: puzzle has no solution")
+	}
+
+	for _, rowIdx := range solution {
+		choice := s.choices[rowIdx]
+		result.Set(choice.col, choice.row, choice.val)
+	}
+
+	return result
+}
+
+// search implements Algorithm X: pick the smallest remaining column, try
+// every row covering it, recurse, and backtrack by uncovering in reverse
+// order.
+func (s *DLXSolver) search(solution *[]int) bool {
+	if s.root.right == &s.root.dlxNode {
+		return true
+	}
+
+	col := s.smallestColumn()
+	if col.size == 0 {
+		return false
+	}
+
+	cover(col)
+
+	for r := col.down; r != &col.dlxNode; r = r.down {
+		*solution = append(*solution, r.row)
+
+		for j := r.right; j != r; j = j.right {
+			cover(j.column)
+		}
+
+		if s.search(solution) {
+			return true
+		}
+
+		for j := r.left; j != r; j = j.left {
+			uncover(j.column)
+		}
+
+		*solution = (*solution)[:len(*solution)-1]
+	}
+
+	uncover(col)
+
+	return false
+}
+
+// smallestColumn picks the column with the fewest remaining rows (the
+// "S heuristic"), which keeps the branching factor as low as possible.
+func (s *DLXSolver) smallestColumn() *dlxColumn {
+	best := (*dlxColumn)(nil)
+	for node := s.root.right; node != &s.root.dlxNode; node = node.right {
+		col := node.column
+		if best == nil || col.size < best.size {
+			best = col
+		}
+	}
+	return best
+}
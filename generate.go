@@ -0,0 +1,445 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// generatorRand is shared by every call to Generate, including concurrent
+// ones - a *rand.Rand isn't safe for concurrent use on its own, so all
+// access goes through shuffledPerm, which holds generatorRandMu.
+var (
+	generatorRandMu sync.Mutex
+	generatorRand   = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// shuffledPerm returns a random permutation of [0,n), safe to call from
+// multiple goroutines at once.
+func shuffledPerm(n int) []int {
+	generatorRandMu.Lock()
+	defer generatorRandMu.Unlock()
+	return generatorRand.Perm(n)
+}
+
+// minCluesByDifficulty is the number of clues Generate aims to leave on the
+// board for each difficulty level. Harder puzzles simply start from fewer
+// givens; how hard they actually turn out to be is then judged by Difficulty.
+var minCluesByDifficulty = map[string]int{
+	"easy":   36,
+	"medium": 30,
+	"hard":   26,
+	"evil":   22,
+}
+
+// Generate produces a new, uniquely solvable 9x9 puzzle at the requested
+// difficulty ("easy", "medium", "hard" or "evil"). It starts from a random
+// full solution, then removes clues one at a time - checking after each
+// removal that the puzzle still has exactly one solution - until it reaches
+// the clue count for the requested level or can't remove any more without
+// losing uniqueness.
+//
+// Generate(d).Difficulty() isn't guaranteed to equal d: Difficulty rates by
+// technique and clue count, and a sparse puzzle can still turn out to be
+// solvable with nothing but naked/hidden singles, which this package has no
+// way to tell apart from a genuinely easy one other than clue count.
+func Generate(difficulty string) Board {
+	minClues, ok := minCluesByDifficulty[difficulty]
+	if !ok {
+		panic("Generate: unknown difficulty " + difficulty)
+	}
+
+	solution := generateFullSolution()
+
+	puzzle := NewBoard()
+	copy(puzzle.cells, solution.cells)
+
+	order := shuffledPerm(len(puzzle.cells))
+	clues := len(puzzle.cells)
+
+	for _, idx := range order {
+		if clues <= minClues {
+			break
+		}
+
+		removed := puzzle.cells[idx]
+		puzzle.cells[idx] = 0
+
+		if !puzzle.IsUnique() {
+			// Removing this clue made the puzzle ambiguous, keep it.
+			puzzle.cells[idx] = removed
+			continue
+		}
+
+		clues--
+	}
+
+	return puzzle
+}
+
+// generateFullSolution produces a random, fully solved 9x9 board by running
+// the backtracking solver with a shuffled candidate order at every step, so
+// repeated calls yield different solved grids.
+func generateFullSolution() Board {
+	b := NewBoard()
+	if !b.solveInnerShuffled(0) {
+		panic("generateFullSolution: failed to produce a full solution")
+	}
+	return b
+}
+
+// solveInnerShuffled is solveInner's counterpart for puzzle generation: it
+// tries candidate values in random order instead of ascending order, so the
+// resulting full solution isn't always the same grid.
+func (b Board) solveInnerShuffled(idx int) bool {
+	idx = b.findNextFieldNotSetAlready(idx)
+
+	if idx == len(b.cells) {
+		return true
+	}
+
+	n := b.N()
+	candidates := shuffledPerm(n)
+
+	for _, c := range candidates {
+		v := c + 1
+		if b.valuePossibleAt(v, idx) {
+			b.cells[idx] = v
+			if b.solveInnerShuffled(idx + 1) {
+				return true
+			}
+		}
+	}
+
+	b.cells[idx] = 0
+
+	return false
+}
+
+// Difficulty estimates how hard b is to solve by hand. It first tries to
+// solve a copy using naked singles, hidden singles and locked candidates
+// (pointing/claiming) - see solvableByLogic - and falls back to the clue
+// count and the backtracking search effort (opsCnt) for anything that
+// needs guessing or the naked/hidden pairs this package doesn't implement.
+func (b Board) Difficulty() string {
+	clues := 0
+	for _, v := range b.cells {
+		if v != 0 {
+			clues++
+		}
+	}
+
+	logicalOnly := b.solvableByLogic()
+
+	switch {
+	case logicalOnly && clues >= minCluesByDifficulty["easy"]:
+		return "easy"
+	case logicalOnly && clues >= minCluesByDifficulty["medium"]:
+		return "medium"
+	case logicalOnly:
+		return "hard"
+	case clues >= minCluesByDifficulty["hard"] && b.searchEffort() < 5000:
+		return "hard"
+	default:
+		return "evil"
+	}
+}
+
+// searchEffort measures how much backtracking a copy of b takes to solve,
+// in units of opsCnt (see Solve). Puzzles that need naked/hidden singles
+// alone never reach here; this only discriminates between "hard" puzzles
+// that still need a little guesswork and genuinely brutal "evil" ones.
+func (b Board) searchEffort() int {
+	working := NewBoardN(b.boxRows, b.boxCols)
+	copy(working.cells, b.cells)
+
+	opsCnt.Store(0)
+	working.solveInner(0)
+	return int(opsCnt.Load())
+}
+
+// candidateSet tracks, for one still-open cell, which values remain
+// possible. It's nil for cells that are already filled in.
+type candidateSet []bool
+
+// solvableByLogic reports whether b can be fully solved by repeatedly
+// applying naked singles (a cell with only one remaining candidate), hidden
+// singles (a candidate that only fits one cell within a row, column or box)
+// and locked candidates (pointing/claiming: a candidate confined to one box
+// within a row/column, or to one row/column within a box, can be eliminated
+// from the rest of that row/column/box) - without ever having to guess.
+func (b Board) solvableByLogic() bool {
+	working := NewBoardN(b.boxRows, b.boxCols)
+	copy(working.cells, b.cells)
+
+	n := working.N()
+	candidates := make([]candidateSet, len(working.cells))
+	for idx, v := range working.cells {
+		if v != 0 {
+			continue
+		}
+		set := make(candidateSet, n+1)
+		for val := 1; val <= n; val++ {
+			set[val] = working.valuePossibleAt(val, idx)
+		}
+		candidates[idx] = set
+	}
+
+	fill := func(idx, v int) {
+		working.cells[idx] = v
+		candidates[idx] = nil
+		for _, peer := range peerIndices(idx, n, working.boxRows, working.boxCols) {
+			if candidates[peer] != nil {
+				candidates[peer][v] = false
+			}
+		}
+	}
+
+	for {
+		progressed := false
+
+		for idx, set := range candidates {
+			if v, ok := set.only(); ok {
+				fill(idx, v)
+				progressed = true
+			}
+		}
+
+		for idx, set := range candidates {
+			if set == nil {
+				continue
+			}
+			if v, ok := working.hiddenSingleAt(idx, set, candidates); ok {
+				fill(idx, v)
+				progressed = true
+			}
+		}
+
+		if working.lockedCandidatesPass(candidates) {
+			progressed = true
+		}
+
+		if working.isComplete() {
+			return true
+		}
+		if !progressed {
+			return false
+		}
+	}
+}
+
+// only reports the sole remaining value in set, if exactly one remains.
+func (set candidateSet) only() (int, bool) {
+	found := 0
+	for v, possible := range set {
+		if possible {
+			if found != 0 {
+				return 0, false
+			}
+			found = v
+		}
+	}
+	return found, found != 0
+}
+
+// hiddenSingleAt reports whether one of idx's remaining candidates is the
+// only one left, among the open cells of its row, column or box, that can
+// still hold that value.
+func (b Board) hiddenSingleAt(idx int, set candidateSet, candidates []candidateSet) (int, bool) {
+	n := b.N()
+	row := idx / n
+	col := idx % n
+	boxRow := row / b.boxRows * b.boxRows
+	boxCol := col / b.boxCols * b.boxCols
+
+	for v := 1; v <= n; v++ {
+		if !set[v] {
+			continue
+		}
+		if onlyCellFor(v, idx, b.rowIndices(row), candidates) ||
+			onlyCellFor(v, idx, b.columnIndices(col), candidates) ||
+			onlyCellFor(v, idx, b.boxIndices(boxRow, boxCol), candidates) {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
+
+// onlyCellFor reports whether, among the open cells of group, idx is the
+// only one whose candidate set still contains v.
+func onlyCellFor(v, idx int, group []int, candidates []candidateSet) bool {
+	for _, other := range group {
+		if other == idx || candidates[other] == nil {
+			continue
+		}
+		if candidates[other][v] {
+			return false
+		}
+	}
+	return true
+}
+
+// lockedCandidatesPass applies one round of the locked-candidates technique
+// to candidates and reports whether it eliminated anything. It covers both
+// directions: pointing (a box's candidates for a value confined to one row
+// or column eliminate that value from the rest of the row/column) and
+// claiming (a row or column's candidates for a value confined to one box
+// eliminate that value from the rest of the box).
+func (b Board) lockedCandidatesPass(candidates []candidateSet) bool {
+	n := b.N()
+	progressed := false
+
+	for boxRow := 0; boxRow < n; boxRow += b.boxRows {
+		for boxCol := 0; boxCol < n; boxCol += b.boxCols {
+			if b.pointingPass(boxRow, boxCol, candidates) {
+				progressed = true
+			}
+		}
+	}
+	for row := 0; row < n; row++ {
+		if b.claimingPass(b.rowIndices(row), candidates) {
+			progressed = true
+		}
+	}
+	for col := 0; col < n; col++ {
+		if b.claimingPass(b.columnIndices(col), candidates) {
+			progressed = true
+		}
+	}
+
+	return progressed
+}
+
+// pointingPass checks the box at (boxRow, boxCol): for each value whose
+// remaining candidate cells all share a row or column, it eliminates that
+// value from the rest of that row/column outside the box.
+func (b Board) pointingPass(boxRow, boxCol int, candidates []candidateSet) bool {
+	n := b.N()
+	box := b.boxIndices(boxRow, boxCol)
+	inBox := make(map[int]bool, len(box))
+	for _, idx := range box {
+		inBox[idx] = true
+	}
+
+	progressed := false
+	for v := 1; v <= n; v++ {
+		row, col := -1, -1
+		sameRow, sameCol, any := true, true, false
+
+		for _, idx := range box {
+			if candidates[idx] == nil || !candidates[idx][v] {
+				continue
+			}
+			any = true
+			r, c := idx/n, idx%n
+			if row == -1 {
+				row = r
+			} else if row != r {
+				sameRow = false
+			}
+			if col == -1 {
+				col = c
+			} else if col != c {
+				sameCol = false
+			}
+		}
+		if !any {
+			continue
+		}
+
+		if sameRow && eliminateExcept(v, b.rowIndices(row), inBox, candidates) {
+			progressed = true
+		}
+		if sameCol && eliminateExcept(v, b.columnIndices(col), inBox, candidates) {
+			progressed = true
+		}
+	}
+
+	return progressed
+}
+
+// claimingPass checks line (a row or column): for each value whose
+// remaining candidate cells all share a box, it eliminates that value from
+// the rest of that box outside line.
+func (b Board) claimingPass(line []int, candidates []candidateSet) bool {
+	n := b.N()
+	inLine := make(map[int]bool, len(line))
+	for _, idx := range line {
+		inLine[idx] = true
+	}
+
+	progressed := false
+	for v := 1; v <= n; v++ {
+		boxRow, boxCol := -1, -1
+		sameBox, any := true, false
+
+		for _, idx := range line {
+			if candidates[idx] == nil || !candidates[idx][v] {
+				continue
+			}
+			any = true
+			r, c := idx/n, idx%n
+			br, bc := r/b.boxRows*b.boxRows, c/b.boxCols*b.boxCols
+			if boxRow == -1 {
+				boxRow, boxCol = br, bc
+			} else if boxRow != br || boxCol != bc {
+				sameBox = false
+			}
+		}
+		if !any || !sameBox {
+			continue
+		}
+
+		if eliminateExcept(v, b.boxIndices(boxRow, boxCol), inLine, candidates) {
+			progressed = true
+		}
+	}
+
+	return progressed
+}
+
+// eliminateExcept removes v from the candidate sets of every open cell in
+// group that isn't in except, reporting whether it removed anything.
+func eliminateExcept(v int, group []int, except map[int]bool, candidates []candidateSet) bool {
+	did := false
+	for _, idx := range group {
+		if except[idx] || candidates[idx] == nil {
+			continue
+		}
+		if candidates[idx][v] {
+			candidates[idx][v] = false
+			did = true
+		}
+	}
+	return did
+}
+
+func (b Board) rowIndices(row int) []int {
+	n := b.N()
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = row*n + i
+	}
+	return indices
+}
+
+func (b Board) columnIndices(col int) []int {
+	n := b.N()
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i*n + col
+	}
+	return indices
+}
+
+func (b Board) boxIndices(boxRow, boxCol int) []int {
+	n := b.N()
+	indices := make([]int, 0, n)
+	for r := boxRow; r < boxRow+b.boxRows; r++ {
+		for c := boxCol; c < boxCol+b.boxCols; c++ {
+			indices = append(indices, r*n+c)
+		}
+	}
+	return indices
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseBoard parses the conventional 81-character Sudoku representation:
+// digits 1-9 for givens and '.' or '0' for empty cells. Whitespace
+// (including newlines) is ignored, so both a single 81-character line and a
+// 9-line grid are accepted.
+func ParseBoard(s string) (Board, error) {
+	b := NewBoard()
+
+	idx := 0
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			continue
+		}
+
+		if idx >= 81 {
+			return Board{}, fmt.Errorf("ParseBoard: too many cells, expected 81")
+		}
+
+		switch {
+		case r == '.' || r == '0':
+			b.cells[idx] = 0
+		case r >= '1' && r <= '9':
+			b.cells[idx] = int(r - '0')
+		default:
+			return Board{}, fmt.Errorf("ParseBoard: invalid character %q at cell %d", r, idx)
+		}
+
+		idx++
+	}
+
+	if idx != 81 {
+		return Board{}, fmt.Errorf("ParseBoard: expected 81 cells, got %d", idx)
+	}
+
+	return b, nil
+}
+
+// Pretty renders b as an N x N grid with separators between its
+// boxRows x boxCols boxes, e.g. for the classic 9x9 case:
+//
+//	+-------+-------+-------+
+//	| 5 3 . | . 7 . | . . . |
+//	| 6 . . | 1 9 5 | . . . |
+//	| . 9 8 | . . . | . 6 . |
+//	+-------+-------+-------+
+//	...
+//
+// Cells are printed as one or two digits, whichever N needs, so this also
+// renders 4x4, 6x6 or 16x16 boards correctly.
+func (b Board) Pretty() string {
+	n := b.N()
+	cellWidth := len(strconv.Itoa(n))
+	separator := strings.Repeat("+"+strings.Repeat("-", (cellWidth+1)*b.boxCols+1), b.boxRows) + "+\n"
+
+	var sb strings.Builder
+
+	for i, v := range b.cells {
+		row := i / n
+		col := i % n
+
+		if col == 0 {
+			if row%b.boxRows == 0 {
+				sb.WriteString(separator)
+			}
+			sb.WriteString("|")
+		}
+
+		if v == 0 {
+			sb.WriteString(" " + strings.Repeat(".", cellWidth))
+		} else {
+			sb.WriteString(" " + fmt.Sprintf("%*d", cellWidth, v))
+		}
+
+		if col%b.boxCols == b.boxCols-1 {
+			sb.WriteString(" |")
+		}
+
+		if col == n-1 {
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString(separator)
+
+	return sb.String()
+}
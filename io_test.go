@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+// parseBoardN is ParseBoard's NxN counterpart, used only by tests: ParseBoard
+// itself is fixed at 81 cells for the classic 9x9 case (see its doc comment).
+func parseBoardN(boxRows, boxCols int, s string) Board {
+	b := NewBoardN(boxRows, boxCols)
+	idx := 0
+	for _, r := range s {
+		if r == '.' {
+			b.cells[idx] = 0
+		} else {
+			b.cells[idx] = int(r - '0')
+		}
+		idx++
+	}
+	return b
+}
+
+func TestPrettyRoundTrip4x4(t *testing.T) {
+	const solved4x4 = "" +
+		"1234" +
+		"3412" +
+		"2143" +
+		"4321"
+
+	b := parseBoardN(2, 2, solved4x4)
+	if !b.isComplete() || !b.isValid() {
+		t.Fatalf("fixture board isn't a valid, complete 4x4 solution")
+	}
+
+	pretty := b.Pretty()
+
+	roundTripped := parseBoardN(2, 2, digitsOnly(pretty))
+	if roundTripped.String() != b.String() {
+		t.Fatalf("round trip through Pretty changed the board:\noriginal: %s\nafter:    %s", b, roundTripped)
+	}
+}
+
+func TestPrettyRoundTrip6x6(t *testing.T) {
+	b := NewBoardN(2, 3)
+	if !b.solveInner(0) {
+		t.Fatalf("failed to produce a full 6x6 solution")
+	}
+
+	pretty := b.Pretty()
+
+	roundTripped := parseBoardN(2, 3, digitsOnly(pretty))
+	if roundTripped.String() != b.String() {
+		t.Fatalf("round trip through Pretty changed the board:\noriginal: %s\nafter:    %s", b, roundTripped)
+	}
+}
+
+// digitsOnly strips everything from a Pretty()-rendered grid except its
+// cell digits, in row-major order, so it can be fed back to parseBoardN.
+func digitsOnly(pretty string) string {
+	var sb []byte
+	for _, r := range pretty {
+		if r >= '0' && r <= '9' {
+			sb = append(sb, byte(r))
+		}
+	}
+	return string(sb)
+}
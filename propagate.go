@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// quiescenceWindow is how long Solver.Solve waits without seeing any further
+// cell settle before it concludes propagation alone has stalled and a guess
+// is needed.
+const quiescenceWindow = 10 * time.Millisecond
+
+// maxForks bounds how many branch Solvers guess is allowed to spawn across an
+// entire Solve call, including branches spawned by branches. Without a cap, a
+// self-contradictory board (givens that break no row, column or box rule
+// yet admit no solution) never settles into a single "zero candidates" cell and instead keeps
+// stalling and re-guessing at every level of recursion, forking exponentially
+// until the process runs out of goroutines. Once the budget is exhausted,
+// guess falls back to the single-threaded, guaranteed-to-terminate
+// deterministicSolve instead of spawning more branches.
+const maxForks = 4096
+
+// cellMsg is an "eliminate value v" notification sent along a peer channel.
+type cellMsg struct {
+	value int
+}
+
+// Solver solves a board by running one goroutine per cell. Each cell
+// goroutine holds the set of candidates still possible for its field and is
+// wired up to its peers (same row, column and box) by elimination channels.
+// Whenever a cell's candidate set collapses to a single value it broadcasts
+// that value to its peers; once every cell has settled, the result is
+// collected back into a Board.
+type Solver struct {
+	peers            [][]chan cellMsg // outbound elimination channels per cell, one per peer
+	done             chan solvedCell
+	boxRows, boxCols int
+	ctx              context.Context
+	cancel           context.CancelFunc
+	forkBudget       *atomic.Int64 // shared with every branch spawned by guess, see maxForks
+}
+
+// solvedCell reports a single cell that has settled on its final value.
+type solvedCell struct {
+	idx, value int
+}
+
+// NewSolver wires up one goroutine per cell of b and seeds their initial
+// candidates/values from the board.
+func NewSolver(b Board) *Solver {
+	return NewSolverContext(context.Background(), b)
+}
+
+// NewSolverContext is NewSolver with an explicit parent context. Cancelling
+// ctx stops every cell goroutine of the returned Solver, and of any branch
+// Solver guess spawns from it, since those inherit ctx as their parent.
+// Solve cancels its own Solver before returning, so callers only need this
+// directly when they want to abort a Solve call that's still in progress.
+func NewSolverContext(ctx context.Context, b Board) *Solver {
+	budget := &atomic.Int64{}
+	budget.Store(maxForks)
+	return newSolverContext(ctx, b, budget)
+}
+
+// newSolverContext is NewSolverContext with an explicit fork budget, shared
+// by every branch guess spawns from the returned Solver so the whole solve
+// tree - not just one level of it - is subject to the same maxForks cap.
+func newSolverContext(ctx context.Context, b Board, budget *atomic.Int64) *Solver {
+	n := b.N()
+	total := n * n
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	s := &Solver{
+		peers:      make([][]chan cellMsg, total),
+		done:       make(chan solvedCell, total),
+		boxRows:    b.boxRows,
+		boxCols:    b.boxCols,
+		ctx:        ctx,
+		cancel:     cancel,
+		forkBudget: budget,
+	}
+
+	inboxes := make([]chan cellMsg, total)
+	for i := range inboxes {
+		inboxes[i] = make(chan cellMsg, total*n) // generous buffer, one solver per puzzle
+	}
+
+	for idx := range s.peers {
+		for _, p := range peerIndices(idx, n, b.boxRows, b.boxCols) {
+			s.peers[idx] = append(s.peers[idx], inboxes[p])
+		}
+	}
+
+	for idx := 0; idx < total; idx++ {
+		go s.runCell(idx, b.cells[idx], n, inboxes[idx])
+	}
+
+	return s
+}
+
+// peerIndices returns the cell indices sharing a row, column or box with idx
+// on an N x N board made up of boxRows x boxCols boxes.
+func peerIndices(idx, n, boxRows, boxCols int) []int {
+	row := idx / n
+	col := idx % n
+	boxRow := row / boxRows * boxRows
+	boxCol := col / boxCols * boxCols
+
+	seen := make(map[int]bool, 3*n)
+	var peers []int
+	add := func(other int) {
+		if other != idx && !seen[other] {
+			seen[other] = true
+			peers = append(peers, other)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		add(row*n + i)
+		add(i*n + col)
+	}
+	for r := boxRow; r < boxRow+boxRows; r++ {
+		for c := boxCol; c < boxCol+boxCols; c++ {
+			add(r*n + c)
+		}
+	}
+
+	return peers
+}
+
+// runCell is the per-cell goroutine. It tracks its own remaining candidates,
+// applies incoming eliminations, and once exactly one candidate is left,
+// reports itself solved and broadcasts the value to its peers. It exits as
+// soon as it settles - there's nothing left for it to do - or when s.ctx is
+// cancelled, which happens once Solve returns or an abandoned guess branch
+// is given up on. Without either of those exits it would block on inbox
+// forever, one goroutine per cell for the lifetime of the process.
+func (s *Solver) runCell(idx, initial, n int, inbox chan cellMsg) {
+	candidates := map[int]bool{}
+	for v := 1; v <= n; v++ {
+		candidates[v] = true
+	}
+
+	settle := func(v int) {
+		for c := range candidates {
+			if c != v {
+				delete(candidates, c)
+			}
+		}
+		s.done <- solvedCell{idx: idx, value: v}
+		for _, peer := range s.peers[idx] {
+			peer <- cellMsg{value: v}
+		}
+	}
+
+	if initial != 0 {
+		settle(initial)
+		return
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case msg := <-inbox:
+			delete(candidates, msg.value)
+			if len(candidates) == 1 {
+				for v := range candidates {
+					settle(v)
+				}
+				return
+			}
+		}
+	}
+}
+
+// Solve runs constraint propagation to completion and returns the solved
+// board. When propagation alone stalls (no cell settles within the
+// quiescence window), it falls back to guessing: it picks the cell with the
+// fewest remaining candidates, forks a copy of the solver per candidate, and
+// races them.
+//
+// The propagation network has no barrier guaranteeing a cell has seen every
+// peer's elimination before it declares itself a singleton, so two peers can
+// race to settle on conflicting values. Solve therefore never trusts
+// isComplete alone: it validates the board it's about to return and, on the
+// rare occasion propagation produced an illegal result, falls back to
+// deterministicSolve to guarantee a correct answer.
+func (s *Solver) Solve(b Board) Board {
+	defer s.cancel()
+
+	if !b.isValid() {
+		// Self-contradictory givens (e.g. a duplicate in the same row,
+		// something ParseBoard never checks for) make the puzzle provably
+		// unsatisfiable before a single cell is filled in. Propagation would
+		// never see this as a clean "zero candidates" cell and would instead
+		// stall and re-guess forever, and even deterministicSolve's exhaustive
+		// backtracking can take combinatorially long to rule out every
+		// assignment on an otherwise-empty board. Since no assignment of the
+		// remaining cells can ever fix a contradiction among the givens
+		// themselves, there's nothing to search for: report no solution
+		// straight away, same as any other failed solve.
+		return b
+	}
+
+	result := NewBoardN(s.boxRows, s.boxCols)
+	copy(result.cells, b.cells)
+
+	remaining := 0
+	for _, v := range result.cells {
+		if v == 0 {
+			remaining++
+		}
+	}
+
+	for remaining > 0 {
+		select {
+		case <-s.ctx.Done():
+			// Abandoned by a parent guess that already found its answer
+			// elsewhere; the result is discarded, so its content doesn't matter.
+			return b
+		case solved := <-s.done:
+			if result.cells[solved.idx] == 0 {
+				result.cells[solved.idx] = solved.value
+				remaining--
+			}
+		case <-time.After(quiescenceWindow):
+			guessed := s.guess(result)
+			if guessed.isComplete() && guessed.isValid() {
+				return guessed
+			}
+			return deterministicSolve(b)
+		}
+	}
+
+	if !result.isValid() {
+		return deterministicSolve(b)
+	}
+
+	return result
+}
+
+// deterministicSolve is the correctness fallback for Solve: it runs the
+// proven-correct backtracking search (Board.solveInner) against a fresh copy
+// of b and returns whatever it finds, complete and legal or not.
+func deterministicSolve(b Board) Board {
+	working := NewBoardN(b.boxRows, b.boxCols)
+	copy(working.cells, b.cells)
+	working.solveInner(0)
+	return working
+}
+
+// guess picks the still-open cell with the fewest possible candidates, forks
+// a fresh Solver per candidate value and races them to completion, returning
+// whichever branch finds a full, legal solution first. Branch Solvers are
+// created with s.ctx as their parent, so once Solve returns and cancels s,
+// every still-running branch - at any recursion depth - is torn down too.
+func (s *Solver) guess(b Board) Board {
+	idx, candidates := fewestCandidates(b)
+	if idx == -1 {
+		return b
+	}
+
+	if s.forkBudget.Add(-int64(len(candidates))) < 0 {
+		// The solve tree has already forked maxForks times; rather than fork
+		// further (the runaway-goroutines failure mode this budget exists to
+		// prevent), give up on guessing and fall back to the single-threaded,
+		// guaranteed-to-terminate deterministicSolve.
+		s.forkBudget.Add(int64(len(candidates)))
+		return deterministicSolve(b)
+	}
+
+	type branchResult struct {
+		board Board
+		ok    bool
+	}
+
+	results := make(chan branchResult, len(candidates))
+
+	for _, n := range candidates {
+		go func(n int) {
+			branch := NewBoardN(b.boxRows, b.boxCols)
+			copy(branch.cells, b.cells)
+			branch.cells[idx] = n
+
+			solved := newSolverContext(s.ctx, branch, s.forkBudget).Solve(branch)
+			results <- branchResult{board: solved, ok: solved.isComplete() && solved.isValid()}
+		}(n)
+	}
+
+	for range candidates {
+		if r := <-results; r.ok {
+			return r.board
+		}
+	}
+
+	return b
+}
+
+// fewestCandidates scans the still-open cells of b and returns the index and
+// candidate list of the one with the smallest number of possibilities, or
+// -1 if every cell is already filled.
+func fewestCandidates(b Board) (int, []int) {
+	best := -1
+	var bestCandidates []int
+
+	n := b.N()
+	for idx := 0; idx < len(b.cells); idx++ {
+		if b.cells[idx] != 0 {
+			continue
+		}
+
+		var candidates []int
+		for v := 1; v <= n; v++ {
+			if b.valuePossibleAt(v, idx) {
+				candidates = append(candidates, v)
+			}
+		}
+
+		if best == -1 || len(candidates) < len(bestCandidates) {
+			best, bestCandidates = idx, candidates
+		}
+	}
+
+	return best, bestCandidates
+}
+
+// isComplete reports whether every cell of b has been filled in.
+func (b Board) isComplete() bool {
+	for _, v := range b.cells {
+		if v == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isValid reports whether b contains no duplicate non-zero value within any
+// row, column or box. It's the legality half of "is this a solution" that
+// isComplete alone doesn't cover - see Solve.
+func (b Board) isValid() bool {
+	n := b.N()
+
+	noDuplicates := func(indices []int) bool {
+		seen := make(map[int]bool, n)
+		for _, idx := range indices {
+			v := b.cells[idx]
+			if v == 0 {
+				continue
+			}
+			if seen[v] {
+				return false
+			}
+			seen[v] = true
+		}
+		return true
+	}
+
+	for i := 0; i < n; i++ {
+		if !noDuplicates(b.rowIndices(i)) || !noDuplicates(b.columnIndices(i)) {
+			return false
+		}
+	}
+
+	for boxRow := 0; boxRow < n; boxRow += b.boxRows {
+		for boxCol := 0; boxCol < n; boxCol += b.boxCols {
+			if !noDuplicates(b.boxIndices(boxRow, boxCol)) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,101 @@
+package main
+
+import "context"
+
+// SolveAll runs the same backtracking search as Solve, but instead of
+// stopping at the first solution it keeps going, collecting up to limit
+// completed boards. A limit of 0 or less means unlimited - use with care on
+// under-constrained boards, as the number of solutions can be huge.
+func (b Board) SolveAll(limit int) []Board {
+	var results []Board
+
+	working := NewBoardN(b.boxRows, b.boxCols)
+	copy(working.cells, b.cells)
+
+	working.solveAllInner(0, limit, &results)
+
+	return results
+}
+
+// SolveAllContext is SolveAll with a context.Context, so long-running
+// enumerations on under-constrained boards can be cancelled. It returns
+// whatever solutions were found before cancellation together with ctx.Err().
+func (b Board) SolveAllContext(ctx context.Context, limit int) ([]Board, error) {
+	var results []Board
+
+	working := NewBoardN(b.boxRows, b.boxCols)
+	copy(working.cells, b.cells)
+
+	err := working.solveAllInnerContext(ctx, 0, limit, &results)
+
+	return results, err
+}
+
+// IsUnique reports whether b has exactly one solution.
+func (b Board) IsUnique() bool {
+	return len(b.SolveAll(2)) == 1
+}
+
+func (b Board) solveAllInner(idx, limit int, results *[]Board) {
+	if limit > 0 && len(*results) >= limit {
+		return
+	}
+
+	idx = b.findNextFieldNotSetAlready(idx)
+
+	if idx == len(b.cells) {
+		solved := NewBoardN(b.boxRows, b.boxCols)
+		copy(solved.cells, b.cells)
+		*results = append(*results, solved)
+		return
+	}
+
+	n := b.N()
+	for i := 1; i <= n; i++ {
+		if limit > 0 && len(*results) >= limit {
+			return
+		}
+		if b.valuePossibleAt(i, idx) {
+			b.cells[idx] = i
+			b.solveAllInner(idx+1, limit, results)
+		}
+	}
+
+	b.cells[idx] = 0
+}
+
+func (b Board) solveAllInnerContext(ctx context.Context, idx, limit int, results *[]Board) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if limit > 0 && len(*results) >= limit {
+		return nil
+	}
+
+	idx = b.findNextFieldNotSetAlready(idx)
+
+	if idx == len(b.cells) {
+		solved := NewBoardN(b.boxRows, b.boxCols)
+		copy(solved.cells, b.cells)
+		*results = append(*results, solved)
+		return nil
+	}
+
+	n := b.N()
+	for i := 1; i <= n; i++ {
+		if limit > 0 && len(*results) >= limit {
+			return nil
+		}
+		if b.valuePossibleAt(i, idx) {
+			b.cells[idx] = i
+			if err := b.solveAllInnerContext(ctx, idx+1, limit, results); err != nil {
+				return err
+			}
+		}
+	}
+
+	b.cells[idx] = 0
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestIsUniqueOnClassicPuzzle(t *testing.T) {
+	b, err := ParseBoard(classicPuzzle)
+	if err != nil {
+		t.Fatalf("ParseBoard: %v", err)
+	}
+
+	if !b.IsUnique() {
+		t.Fatalf("expected the classic puzzle to have exactly one solution")
+	}
+
+	solutions := b.SolveAll(0)
+	if len(solutions) != 1 {
+		t.Fatalf("SolveAll(0) found %d solutions, want 1", len(solutions))
+	}
+}
+
+func TestSolveAllRespectsLimit(t *testing.T) {
+	// An all-empty board has a huge number of solutions; SolveAll must stop
+	// as soon as it hits the requested limit instead of enumerating them all.
+	b := NewBoard()
+
+	const limit = 3
+	solutions := b.SolveAll(limit)
+	if len(solutions) != limit {
+		t.Fatalf("SolveAll(%d) returned %d solutions, want exactly %d", limit, len(solutions), limit)
+	}
+
+	if b.IsUnique() {
+		t.Fatalf("expected an empty board to have more than one solution")
+	}
+}
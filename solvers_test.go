@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+// classicPuzzle is a well-known 9x9 puzzle with a single solution, used to
+// cross-check that the different solvers in this package all agree.
+const classicPuzzle = "" +
+	"53..7...." +
+	"6..195..." +
+	".98....6." +
+	"8...6...3" +
+	"4..8.3..1" +
+	"7...2...6" +
+	".6....28." +
+	"...419..5" +
+	"....8..79"
+
+func TestSolversAgreeOnClassicPuzzle(t *testing.T) {
+	b, err := ParseBoard(classicPuzzle)
+	if err != nil {
+		t.Fatalf("ParseBoard: %v", err)
+	}
+
+	backtracked := NewBoardN(b.boxRows, b.boxCols)
+	copy(backtracked.cells, b.cells)
+	if !backtracked.solveInner(0) {
+		t.Fatalf("backtracking solveInner failed to solve the classic puzzle")
+	}
+
+	dlxSolved := NewDLXSolver(b).Solve()
+
+	propagated := NewSolver(b).Solve(b)
+
+	for _, solved := range []Board{backtracked, dlxSolved, propagated} {
+		if !solved.isComplete() || !solved.isValid() {
+			t.Fatalf("incomplete or invalid solution: %s", solved)
+		}
+	}
+
+	if backtracked.String() != dlxSolved.String() {
+		t.Errorf("DLX and backtracking disagree:\nbacktracking: %s\nDLX: %s", backtracked, dlxSolved)
+	}
+	if backtracked.String() != propagated.String() {
+		t.Errorf("propagation and backtracking disagree:\nbacktracking: %s\npropagation: %s", backtracked, propagated)
+	}
+}